@@ -0,0 +1,234 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/google/uuid"
+	"github.com/grafana/frigg/friggdb/backend"
+)
+
+type readerWriter struct {
+	cfg       *Config
+	container azblob.ContainerURL
+}
+
+// New creates a backend.Reader, backend.Writer and backend.Compactor backed by Azure Blob Storage.
+func New(cfg *Config) (backend.Reader, backend.Writer, backend.Compactor, error) {
+	credential, err := azblob.NewSharedKeyCredential(cfg.StorageAccountName, cfg.StorageAccountKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{})
+
+	u, err := url.Parse(fmt.Sprintf("https://%s.blob.core.windows.net/%s", cfg.StorageAccountName, cfg.ContainerName))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rw := &readerWriter{
+		cfg:       cfg,
+		container: azblob.NewContainerURL(*u, pipeline),
+	}
+
+	return rw, rw, rw, nil
+}
+
+func (rw *readerWriter) Write(ctx context.Context, blockID uuid.UUID, tenantID string, meta *backend.BlockMeta, bBloom []byte, bIndex []byte, objectFilePath string) error {
+	err := rw.writeAll(ctx, rw.bloomFileName(blockID, tenantID), bBloom)
+	if err != nil {
+		return err
+	}
+
+	err = rw.writeAll(ctx, rw.indexFileName(blockID, tenantID), bIndex)
+	if err != nil {
+		return err
+	}
+
+	// copy traces file.
+	src, err := os.Open(objectFilePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = azblob.UploadFileToBlockBlob(ctx, src, rw.blockBlobURL(rw.objectFileName(blockID, tenantID)), azblob.UploadToBlockBlobOptions{})
+	if err != nil {
+		return err
+	}
+
+	bMeta, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	// write meta last.  this will prevent blocklist from returning a partial block
+	return rw.writeAll(ctx, rw.metaFileName(blockID, tenantID), bMeta)
+}
+
+func (rw *readerWriter) Tenants() ([]string, error) {
+	var warning error
+	tenants := make([]string, 0)
+
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		list, err := rw.container.ListBlobsHierarchySegment(context.Background(), marker, "/", azblob.ListBlobsSegmentOptions{})
+		if err != nil {
+			warning = err
+			break
+		}
+
+		for _, p := range list.Segment.BlobPrefixes {
+			tenants = append(tenants, strings.TrimSuffix(p.Name, "/"))
+		}
+
+		marker = list.NextMarker
+	}
+
+	return tenants, warning
+}
+
+func (rw *readerWriter) Blocks(tenantID string) ([]uuid.UUID, error) {
+	var warning error
+	blocks := make([]uuid.UUID, 0)
+
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		list, err := rw.container.ListBlobsHierarchySegment(context.Background(), marker, "/", azblob.ListBlobsSegmentOptions{
+			Prefix: tenantID + "/",
+		})
+		if err != nil {
+			warning = err
+			break
+		}
+
+		for _, p := range list.Segment.BlobPrefixes {
+			idString := strings.TrimSuffix(strings.TrimPrefix(p.Name, tenantID+"/"), "/")
+			blockID, err := uuid.Parse(idString)
+			if err != nil {
+				warning = fmt.Errorf("failed parse on blockID %s: %v", idString, err)
+				continue
+			}
+			blocks = append(blocks, blockID)
+		}
+
+		marker = list.NextMarker
+	}
+
+	return blocks, warning
+}
+
+func (rw *readerWriter) BlockMeta(blockID uuid.UUID, tenantID string) (*backend.BlockMeta, error) {
+	name := rw.metaFileName(blockID, tenantID)
+
+	bytes, err := rw.readAll(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &backend.BlockMeta{}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (rw *readerWriter) Bloom(blockID uuid.UUID, tenantID string) ([]byte, error) {
+	name := rw.bloomFileName(blockID, tenantID)
+	return rw.readAll(context.Background(), name)
+}
+
+func (rw *readerWriter) Index(blockID uuid.UUID, tenantID string) ([]byte, error) {
+	name := rw.indexFileName(blockID, tenantID)
+	return rw.readAll(context.Background(), name)
+}
+
+func (rw *readerWriter) Object(blockID uuid.UUID, tenantID string, start uint64, buffer []byte) error {
+	name := rw.objectFileName(blockID, tenantID)
+	return rw.readRange(context.Background(), name, int64(start), buffer)
+}
+
+func (rw *readerWriter) ObjectStream(blockID uuid.UUID, tenantID string, start int64, length int64) (io.ReadCloser, error) {
+	name := rw.objectFileName(blockID, tenantID)
+
+	resp, err := rw.blockBlobURL(name).Download(context.Background(), start, length, azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body(azblob.RetryReaderOptions{}), nil
+}
+
+func (rw *readerWriter) Shutdown() {
+
+}
+
+func (rw *readerWriter) metaFileName(blockID uuid.UUID, tenantID string) string {
+	return path.Join(rw.rootPath(blockID, tenantID), "meta.json")
+}
+
+func (rw *readerWriter) bloomFileName(blockID uuid.UUID, tenantID string) string {
+	return path.Join(rw.rootPath(blockID, tenantID), "bloom")
+}
+
+func (rw *readerWriter) indexFileName(blockID uuid.UUID, tenantID string) string {
+	return path.Join(rw.rootPath(blockID, tenantID), "index")
+}
+
+func (rw *readerWriter) objectFileName(blockID uuid.UUID, tenantID string) string {
+	return path.Join(rw.rootPath(blockID, tenantID), "data")
+}
+
+func (rw *readerWriter) rootPath(blockID uuid.UUID, tenantID string) string {
+	return path.Join(tenantID, blockID.String())
+}
+
+func (rw *readerWriter) blockBlobURL(name string) azblob.BlockBlobURL {
+	return rw.container.NewBlockBlobURL(name)
+}
+
+func (rw *readerWriter) writeAll(ctx context.Context, name string, b []byte) error {
+	_, err := azblob.UploadBufferToBlockBlob(ctx, b, rw.blockBlobURL(name), azblob.UploadToBlockBlobOptions{})
+	return err
+}
+
+func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, error) {
+	resp, err := rw.blockBlobURL(name).Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	body := resp.Body(azblob.RetryReaderOptions{})
+	defer body.Close()
+
+	return ioutil.ReadAll(body)
+}
+
+func (rw *readerWriter) readRange(ctx context.Context, name string, offset int64, buffer []byte) error {
+	resp, err := rw.blockBlobURL(name).Download(ctx, offset, int64(len(buffer)), azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		return err
+	}
+
+	body := resp.Body(azblob.RetryReaderOptions{})
+	defer body.Close()
+
+	b := &bytes.Buffer{}
+	_, err = b.ReadFrom(body)
+	if err != nil {
+		return err
+	}
+
+	copy(buffer, b.Bytes())
+	return nil
+}
@@ -0,0 +1,28 @@
+package azure
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/grafana/frigg/friggdb/backend"
+)
+
+// ErrNotImplemented is returned by the Azure backend's compactor methods until
+// they get a real implementation mirroring the GCS backend's.
+var ErrNotImplemented = errors.New("not implemented")
+
+func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	return ErrNotImplemented
+}
+
+func (rw *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	return ErrNotImplemented
+}
+
+func (rw *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
+	return nil, ErrNotImplemented
+}
+
+func (rw *readerWriter) CompactedBlocks(tenantID string) (backend.CompactedBlockIterator, error) {
+	return nil, ErrNotImplemented
+}
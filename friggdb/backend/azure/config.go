@@ -0,0 +1,8 @@
+package azure
+
+// Config holds the Azure Blob Storage backend configuration.
+type Config struct {
+	StorageAccountName string `yaml:"storage_account_name"`
+	StorageAccountKey  string `yaml:"storage_account_key"`
+	ContainerName      string `yaml:"container_name"`
+}
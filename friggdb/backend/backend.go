@@ -0,0 +1,72 @@
+package backend
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlockMeta is the metadata associated with a single block in a bucket.  It is
+// written last so that a block is only ever considered valid once it is present.
+type BlockMeta struct {
+	BlockID      uuid.UUID `json:"blockID"`
+	TenantID     string    `json:"tenantID"`
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+	TotalObjects int       `json:"totalObjects"`
+	Size         uint64    `json:"size"`
+}
+
+// Reader is capable of reading data from a backend.
+type Reader interface {
+	Tenants() ([]string, error)
+	Blocks(tenantID string) ([]uuid.UUID, error)
+	BlockMeta(blockID uuid.UUID, tenantID string) (*BlockMeta, error)
+	Bloom(blockID uuid.UUID, tenantID string) ([]byte, error)
+	Index(blockID uuid.UUID, tenantID string) ([]byte, error)
+	Object(blockID uuid.UUID, tenantID string, start uint64, buffer []byte) error
+	// ObjectStream returns a reader over length bytes of the object starting at start,
+	// letting callers avoid pre-sizing a buffer or copying into one they don't need.
+	ObjectStream(blockID uuid.UUID, tenantID string, start int64, length int64) (io.ReadCloser, error)
+}
+
+// Writer is capable of writing data to a backend.
+type Writer interface {
+	Write(ctx context.Context, blockID uuid.UUID, tenantID string, meta *BlockMeta, bBloom []byte, bIndex []byte, objectFilePath string) error
+}
+
+// CompactedBlockMeta is the metadata written for a block once the compactor has
+// folded it into another block and it is safe to eventually clear.
+type CompactedBlockMeta struct {
+	BlockMeta
+	CompactedTime time.Time `json:"compactedTime"`
+}
+
+// CompactedBlockIterator iterates over the blocks a backend has marked compacted
+// for a tenant. Next returns io.EOF once exhausted.
+type CompactedBlockIterator interface {
+	Next() (uuid.UUID, error)
+}
+
+// Compactor is capable of compacting and clearing blocks in a backend.
+//
+// Only the GCS backend implements compaction fully today; S3 and Azure return
+// ErrNotImplemented from these methods, and their Blocks() does not filter out
+// compacted blocks.
+type Compactor interface {
+	Shutdown()
+	// MarkBlockCompacted records that blockID has been folded into another block and
+	// is a candidate for clearing once it's no longer needed by in-flight queries.
+	MarkBlockCompacted(blockID uuid.UUID, tenantID string) error
+	// ClearBlock permanently deletes a compacted block's bloom, index, data and
+	// compaction marker, then its meta.json last so a partial delete never leaves
+	// a live block pointing at missing data.
+	ClearBlock(blockID uuid.UUID, tenantID string) error
+	// CompactedBlockMeta reads the compaction marker for blockID.
+	CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*CompactedBlockMeta, error)
+	// CompactedBlocks iterates the blocks marked compacted for tenantID so the
+	// compactor loop can find clearing candidates.
+	CompactedBlocks(tenantID string) (CompactedBlockIterator, error)
+}
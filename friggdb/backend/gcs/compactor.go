@@ -0,0 +1,175 @@
+package gcs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/uuid"
+	"github.com/grafana/frigg/friggdb/backend"
+	"github.com/grafana/frigg/friggdb/log"
+)
+
+func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	ctx := log.WithFields(context.Background(), "tenantID", tenantID, "blockID", blockID.String(), "operation", "MarkBlockCompacted")
+
+	meta, err := rw.BlockMeta(blockID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	compactedMeta := &backend.CompactedBlockMeta{
+		BlockMeta:     *meta,
+		CompactedTime: time.Now(),
+	}
+
+	bMeta, err := json.Marshal(compactedMeta)
+	if err != nil {
+		err = fmt.Errorf("marshal compacted meta for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
+		return err
+	}
+
+	err = rw.writeAll(ctx, rw.compactedMetaFileName(blockID, tenantID), tenantID, bMeta)
+	if err != nil {
+		err = fmt.Errorf("write compacted meta for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// ClearBlock deletes a compacted block's bloom, index, data and compaction marker,
+// then its meta.json last so a partial delete never leaves a live block referencing
+// missing data.
+func (rw *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	ctx := log.WithFields(context.Background(), "tenantID", tenantID, "blockID", blockID.String(), "operation", "ClearBlock")
+
+	names := []string{
+		rw.bloomFileName(blockID, tenantID),
+		rw.indexFileName(blockID, tenantID),
+		rw.objectFileName(blockID, tenantID),
+		rw.compactedMetaFileName(blockID, tenantID),
+	}
+
+	for _, name := range names {
+		err := rw.deleteObject(ctx, name)
+		if err != nil {
+			err = fmt.Errorf("delete %s for block %s tenant %s: %w", name, blockID, tenantID, err)
+			log.LogIf(ctx, err)
+			return err
+		}
+	}
+
+	err := rw.deleteObject(ctx, rw.metaFileName(blockID, tenantID))
+	if err != nil {
+		err = fmt.Errorf("delete meta for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+func (rw *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
+	ctx := log.WithFields(context.Background(), "tenantID", tenantID, "blockID", blockID.String(), "operation", "CompactedBlockMeta")
+	name := rw.compactedMetaFileName(blockID, tenantID)
+
+	bytes, err := rw.readAll(ctx, name, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &backend.CompactedBlockMeta{}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		err = fmt.Errorf("unmarshal compacted meta for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// CompactedBlocks iterates the blocks under tenantID that carry a compaction marker.
+func (rw *readerWriter) CompactedBlocks(tenantID string) (backend.CompactedBlockIterator, error) {
+	ctx := log.WithFields(context.Background(), "tenantID", tenantID, "operation", "CompactedBlocks")
+
+	blocks, err := rw.blocks(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted := make([]uuid.UUID, 0, len(blocks))
+	for _, blockID := range blocks {
+		ok, err := rw.isCompacted(blockID, tenantID)
+		if err != nil {
+			log.LogIf(ctx, err, "blockID", blockID.String(), "note", "skipping entry")
+			continue
+		}
+		if ok {
+			compacted = append(compacted, blockID)
+		}
+	}
+
+	return &compactedBlockIterator{blocks: compacted}, nil
+}
+
+// isCompacted reports whether blockID has a compaction marker. A retryable error
+// is retried through the pacer; any error left after that is returned to the
+// caller rather than being treated as "not compacted", so a flaky Attrs call
+// can't make an already-compacted block reappear in Blocks().
+func (rw *readerWriter) isCompacted(blockID uuid.UUID, tenantID string) (bool, error) {
+	exists := false
+
+	err := rw.pacer.Call(func() (bool, error) {
+		_, err := rw.bucket.Object(rw.compactedMetaFileName(blockID, tenantID)).Attrs(context.Background())
+		if err == storage.ErrObjectNotExist {
+			exists = false
+			return false, nil
+		}
+		if err != nil {
+			return shouldRetry(err), err
+		}
+
+		exists = true
+		return false, nil
+	})
+
+	return exists, err
+}
+
+func (rw *readerWriter) deleteObject(ctx context.Context, name string) error {
+	return rw.pacer.Call(func() (bool, error) {
+		err := rw.bucket.Object(name).Delete(ctx)
+		if err == nil || err == storage.ErrObjectNotExist {
+			return false, nil
+		}
+		return shouldRetry(err), err
+	})
+}
+
+func (rw *readerWriter) compactedMetaFileName(blockID uuid.UUID, tenantID string) string {
+	return path.Join(rw.rootPath(blockID, tenantID), "compacted.json")
+}
+
+type compactedBlockIterator struct {
+	blocks []uuid.UUID
+	idx    int
+}
+
+func (i *compactedBlockIterator) Next() (uuid.UUID, error) {
+	if i.idx >= len(i.blocks) {
+		return uuid.UUID{}, io.EOF
+	}
+
+	blockID := i.blocks[i.idx]
+	i.idx++
+
+	return blockID, nil
+}
@@ -0,0 +1,26 @@
+package gcs
+
+import "time"
+
+// KeysetFunc returns a per-tenant encryption override. A nil return for both
+// values means "use the Config-level EncryptionKey/KMSKeyName for this tenant".
+type KeysetFunc func(tenantID string) (encryptionKey []byte, kmsKeyName string)
+
+// Config holds the GCS backend configuration.
+type Config struct {
+	BucketName      string        `yaml:"bucket_name"`
+	ChunkBufferSize int           `yaml:"chunk_buffer_size"`
+	PacerMinSleep   time.Duration `yaml:"pacer_min_sleep"`
+	PacerMaxSleep   time.Duration `yaml:"pacer_max_sleep"`
+	PacerMaxRetries int           `yaml:"pacer_max_retries"`
+
+	// EncryptionKey is a 32-byte AES-256 customer-supplied encryption key (CSEK).
+	// Mutually exclusive with KMSKeyName.
+	EncryptionKey []byte `yaml:"encryption_key"`
+	// KMSKeyName is the fully-qualified resource name of a Cloud KMS key used for
+	// customer-managed encryption (CMEK). Mutually exclusive with EncryptionKey.
+	KMSKeyName string `yaml:"kms_key_name"`
+	// Keyset overrides EncryptionKey/KMSKeyName on a per-tenant basis, so
+	// multi-tenant deployments can hold separate keys per tenant.
+	Keyset KeysetFunc `yaml:"-"`
+}
@@ -0,0 +1,20 @@
+package gcs
+
+import "fmt"
+
+// keyFor resolves the CSEK/CMEK override for tenantID, falling back to the
+// Config-level EncryptionKey/KMSKeyName when no per-tenant override applies.
+// It rejects a Keyset override that configures both CSEK and CMEK for the
+// same tenant, the same validation New() applies to the Config-level fields.
+func (rw *readerWriter) keyFor(tenantID string) (encryptionKey []byte, kmsKeyName string, err error) {
+	if rw.cfg.Keyset != nil {
+		if key, kms := rw.cfg.Keyset(tenantID); len(key) > 0 || kms != "" {
+			if len(key) > 0 && kms != "" {
+				return nil, "", fmt.Errorf("tenant %s keyset configures both EncryptionKey (CSEK) and KMSKeyName (CMEK)", tenantID)
+			}
+			return key, kms, nil
+		}
+	}
+
+	return rw.cfg.EncryptionKey, rw.cfg.KMSKeyName, nil
+}
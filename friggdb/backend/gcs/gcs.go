@@ -14,6 +14,8 @@ import (
 	"cloud.google.com/go/storage"
 	"github.com/google/uuid"
 	"github.com/grafana/frigg/friggdb/backend"
+	"github.com/grafana/frigg/friggdb/backend/gcs/pacer"
+	"github.com/grafana/frigg/friggdb/log"
 	"google.golang.org/api/iterator"
 )
 
@@ -21,9 +23,14 @@ type readerWriter struct {
 	cfg    *Config
 	client *storage.Client
 	bucket *storage.BucketHandle
+	pacer  *pacer.Pacer
 }
 
 func New(cfg *Config) (backend.Reader, backend.Writer, backend.Compactor, error) {
+	if len(cfg.EncryptionKey) > 0 && cfg.KMSKeyName != "" {
+		return nil, nil, nil, fmt.Errorf("cannot configure both EncryptionKey (CSEK) and KMSKeyName (CMEK)")
+	}
+
 	ctx := context.Background()
 
 	option, err := instrumentation(ctx, storage.ScopeReadWrite)
@@ -42,49 +49,74 @@ func New(cfg *Config) (backend.Reader, backend.Writer, backend.Compactor, error)
 		cfg:    cfg,
 		client: client,
 		bucket: bucket,
+		pacer: pacer.New(pacer.Config{
+			MinSleep:   cfg.PacerMinSleep,
+			MaxSleep:   cfg.PacerMaxSleep,
+			MaxRetries: cfg.PacerMaxRetries,
+		}),
 	}
 
 	return rw, rw, rw, nil
 }
 
 func (rw *readerWriter) Write(ctx context.Context, blockID uuid.UUID, tenantID string, meta *backend.BlockMeta, bBloom []byte, bIndex []byte, objectFilePath string) error {
+	ctx = log.WithFields(ctx, "tenantID", tenantID, "blockID", blockID.String(), "operation", "Write")
 
-	err := rw.writeAll(ctx, rw.bloomFileName(blockID, tenantID), bBloom)
+	err := rw.writeAll(ctx, rw.bloomFileName(blockID, tenantID), tenantID, bBloom)
 	if err != nil {
+		err = fmt.Errorf("write bloom for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
 		return err
 	}
 
-	err = rw.writeAll(ctx, rw.indexFileName(blockID, tenantID), bIndex)
+	err = rw.writeAll(ctx, rw.indexFileName(blockID, tenantID), tenantID, bIndex)
 	if err != nil {
+		err = fmt.Errorf("write index for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
 		return err
 	}
 
 	// copy traces file.
 	if !fileExists(objectFilePath) {
-		return fmt.Errorf("object file not found %s", objectFilePath)
+		err = fmt.Errorf("object file not found %s", objectFilePath)
+		log.LogIf(ctx, err)
+		return err
 	}
 
 	src, err := os.Open(objectFilePath)
 	if err != nil {
+		err = fmt.Errorf("open object file for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
 		return err
 	}
 	defer src.Close()
 
-	w := rw.writer(ctx, rw.objectFileName(blockID, tenantID))
+	w, err := rw.writer(ctx, rw.objectFileName(blockID, tenantID), tenantID)
+	if err != nil {
+		err = fmt.Errorf("write data for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
+		return err
+	}
 	defer w.Close()
 	_, err = io.Copy(w, src)
 	if err != nil {
+		err = fmt.Errorf("write data for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
 		return err
 	}
 
 	bMeta, err := json.Marshal(meta)
 	if err != nil {
+		err = fmt.Errorf("marshal meta for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
 		return err
 	}
 
 	// write meta last.  this will prevent blocklist from returning a partial block
-	err = rw.writeAll(ctx, rw.metaFileName(blockID, tenantID), bMeta)
+	err = rw.writeAll(ctx, rw.metaFileName(blockID, tenantID), tenantID, bMeta)
 	if err != nil {
+		err = fmt.Errorf("write meta for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
 		return err
 	}
 
@@ -92,67 +124,119 @@ func (rw *readerWriter) Write(ctx context.Context, blockID uuid.UUID, tenantID s
 }
 
 func (rw *readerWriter) Tenants() ([]string, error) {
+	ctx := log.WithFields(context.Background(), "operation", "Tenants")
 	var warning error
-	iter := rw.bucket.Objects(context.Background(), &storage.Query{
-		Delimiter: "/",
-		Versions:  false,
-	})
-
 	tenants := make([]string, 0)
 
-	for {
-		attrs, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			warning = err
-			continue
+	err := rw.pacer.Call(func() (bool, error) {
+		tenants = tenants[:0]
+		iter := rw.bucket.Objects(context.Background(), &storage.Query{
+			Delimiter: "/",
+			Versions:  false,
+		})
+
+		for {
+			attrs, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				if shouldRetry(err) {
+					return true, err
+				}
+				warning = err
+				log.LogIf(ctx, warning, "note", "skipping entry")
+				continue
+			}
+			tenants = append(tenants, strings.TrimSuffix(attrs.Prefix, "/"))
 		}
-		tenants = append(tenants, strings.TrimSuffix(attrs.Prefix, "/"))
+
+		return false, nil
+	})
+	if err != nil {
+		warning = fmt.Errorf("list tenants: %w", err)
+		log.LogIf(ctx, warning)
 	}
 
 	return tenants, warning
 }
 
+// Blocks lists the blockIDs for tenantID, skipping any that have already been
+// marked compacted. If a block's compacted state can't be determined, it is
+// left out of the result and warning is set, rather than assumed uncompacted.
 func (rw *readerWriter) Blocks(tenantID string) ([]uuid.UUID, error) {
-	var warning error
+	ctx := log.WithFields(context.Background(), "tenantID", tenantID, "operation", "Blocks")
+	all, warning := rw.blocks(tenantID)
 
-	ctx := context.Background()
-	iter := rw.bucket.Objects(ctx, &storage.Query{
-		Prefix:    tenantID + "/",
-		Delimiter: "/",
-		Versions:  false,
-	})
-
-	blocks := make([]uuid.UUID, 0)
-	for {
-		attrs, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
+	blocks := make([]uuid.UUID, 0, len(all))
+	for _, blockID := range all {
+		compacted, err := rw.isCompacted(blockID, tenantID)
 		if err != nil {
 			warning = err
+			log.LogIf(ctx, err, "blockID", blockID.String(), "note", "skipping entry")
 			continue
 		}
+		if !compacted {
+			blocks = append(blocks, blockID)
+		}
+	}
 
-		idString := strings.TrimSuffix(strings.TrimPrefix(attrs.Prefix, tenantID+"/"), "/")
-		blockID, err := uuid.Parse(idString)
-		if err != nil {
-			warning = fmt.Errorf("failed parse on blockID %s: %v", idString, err)
-			continue
+	return blocks, warning
+}
+
+func (rw *readerWriter) blocks(tenantID string) ([]uuid.UUID, error) {
+	ctx := log.WithFields(context.Background(), "tenantID", tenantID, "operation", "Blocks")
+	var warning error
+	blocks := make([]uuid.UUID, 0)
+
+	err := rw.pacer.Call(func() (bool, error) {
+		blocks = blocks[:0]
+		iter := rw.bucket.Objects(ctx, &storage.Query{
+			Prefix:    tenantID + "/",
+			Delimiter: "/",
+			Versions:  false,
+		})
+
+		for {
+			attrs, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				if shouldRetry(err) {
+					return true, err
+				}
+				warning = err
+				log.LogIf(ctx, warning, "note", "skipping entry")
+				continue
+			}
+
+			idString := strings.TrimSuffix(strings.TrimPrefix(attrs.Prefix, tenantID+"/"), "/")
+			blockID, err := uuid.Parse(idString)
+			if err != nil {
+				warning = fmt.Errorf("failed parse on blockID %s: %v", idString, err)
+				log.LogIf(ctx, warning)
+				continue
+			}
+
+			blocks = append(blocks, blockID)
 		}
 
-		blocks = append(blocks, blockID)
+		return false, nil
+	})
+	if err != nil {
+		warning = fmt.Errorf("list blocks for tenant %s: %w", tenantID, err)
+		log.LogIf(ctx, warning)
 	}
 
 	return blocks, warning
 }
 
 func (rw *readerWriter) BlockMeta(blockID uuid.UUID, tenantID string) (*backend.BlockMeta, error) {
+	ctx := log.WithFields(context.Background(), "tenantID", tenantID, "blockID", blockID.String(), "operation", "BlockMeta")
 	name := rw.metaFileName(blockID, tenantID)
 
-	bytes, err := rw.readAll(context.Background(), name)
+	bytes, err := rw.readAll(ctx, name, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +244,8 @@ func (rw *readerWriter) BlockMeta(blockID uuid.UUID, tenantID string) (*backend.
 	out := &backend.BlockMeta{}
 	err = json.Unmarshal(bytes, out)
 	if err != nil {
+		err = fmt.Errorf("unmarshal meta for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
 		return nil, err
 	}
 
@@ -167,18 +253,67 @@ func (rw *readerWriter) BlockMeta(blockID uuid.UUID, tenantID string) (*backend.
 }
 
 func (rw *readerWriter) Bloom(blockID uuid.UUID, tenantID string) ([]byte, error) {
+	ctx := log.WithFields(context.Background(), "tenantID", tenantID, "blockID", blockID.String(), "operation", "Bloom")
 	name := rw.bloomFileName(blockID, tenantID)
-	return rw.readAll(context.Background(), name)
+	return rw.readAll(ctx, name, tenantID)
 }
 
 func (rw *readerWriter) Index(blockID uuid.UUID, tenantID string) ([]byte, error) {
+	ctx := log.WithFields(context.Background(), "tenantID", tenantID, "blockID", blockID.String(), "operation", "Index")
 	name := rw.indexFileName(blockID, tenantID)
-	return rw.readAll(context.Background(), name)
+	return rw.readAll(ctx, name, tenantID)
 }
 
+// Object is a thin adapter over ObjectStream for callers that want the whole
+// range copied into a pre-sized buffer rather than streaming it themselves.
 func (rw *readerWriter) Object(blockID uuid.UUID, tenantID string, start uint64, buffer []byte) error {
+	ctx := log.WithFields(context.Background(), "tenantID", tenantID, "blockID", blockID.String(), "operation", "Object")
+
+	r, err := rw.ObjectStream(blockID, tenantID, int64(start), int64(len(buffer)))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	_, err = io.ReadFull(r, buffer)
+	if err != nil {
+		err = fmt.Errorf("read object for block %s tenant %s: %w", blockID, tenantID, err)
+		log.LogIf(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// ObjectStream returns a reader over length bytes of the object starting at start.
+// It hands back the raw *storage.Reader so callers can stream decompression or
+// index/bloom parsing without an extra buffer copy.
+func (rw *readerWriter) ObjectStream(blockID uuid.UUID, tenantID string, start int64, length int64) (io.ReadCloser, error) {
 	name := rw.objectFileName(blockID, tenantID)
-	return rw.readRange(context.Background(), name, int64(start), buffer)
+
+	var r io.ReadCloser
+	err := rw.pacer.Call(func() (bool, error) {
+		obj := rw.bucket.Object(name)
+		key, _, err := rw.keyFor(tenantID)
+		if err != nil {
+			return false, err
+		}
+		if len(key) > 0 {
+			obj = obj.Key(key)
+		}
+
+		reader, err := obj.NewRangeReader(context.Background(), start, length)
+		if err != nil {
+			return shouldRetry(err), err
+		}
+		r = reader
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
 }
 
 func (rw *readerWriter) Shutdown() {
@@ -205,71 +340,109 @@ func (rw *readerWriter) rootPath(blockID uuid.UUID, tenantID string) string {
 	return path.Join(tenantID, blockID.String())
 }
 
-func (rw *readerWriter) writeAll(ctx context.Context, name string, b []byte) error {
-	w := rw.writer(ctx, name)
-	defer w.Close()
+func (rw *readerWriter) writeAll(ctx context.Context, name string, tenantID string, b []byte) error {
+	return rw.pacer.Call(func() (bool, error) {
+		w, err := rw.writer(ctx, name, tenantID)
+		if err != nil {
+			return false, err
+		}
+		defer w.Close()
 
-	_, err := w.Write(b)
-	if err != nil {
-		return err
-	}
+		_, err = w.Write(b)
+		if err != nil {
+			return shouldRetry(err), err
+		}
 
-	return nil
+		return false, nil
+	})
 }
 
-func (rw *readerWriter) writer(ctx context.Context, name string) *storage.Writer {
-	w := rw.bucket.Object(name).NewWriter(ctx)
-	w.ChunkSize = rw.cfg.ChunkBufferSize
+// writer returns a storage.Writer for name, applying the CSEK/CMEK encryption
+// configured for tenantID, if any.
+func (rw *readerWriter) writer(ctx context.Context, name string, tenantID string) (*storage.Writer, error) {
+	obj := rw.bucket.Object(name)
 
-	return w
-}
-
-func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, error) {
-	r, err := rw.bucket.Object(name).NewReader(ctx)
+	key, kmsKeyName, err := rw.keyFor(tenantID)
 	if err != nil {
 		return nil, err
 	}
-	defer r.Close()
+	if len(key) > 0 {
+		obj = obj.Key(key)
+	}
+
+	w := obj.NewWriter(ctx)
+	w.ChunkSize = rw.cfg.ChunkBufferSize
+	if kmsKeyName != "" {
+		w.KMSKeyName = kmsKeyName
+	}
 
-	return ioutil.ReadAll(r)
+	return w, nil
 }
 
-func (rw *readerWriter) readAllWithModTime(ctx context.Context, name string) ([]byte, time.Time, error) {
-	r, err := rw.bucket.Object(name).NewReader(ctx)
-	if err != nil {
-		return nil, time.Time{}, err
-	}
-	defer r.Close()
+func (rw *readerWriter) readAll(ctx context.Context, name string, tenantID string) ([]byte, error) {
+	var b []byte
 
-	bytes, err := ioutil.ReadAll(r)
+	err := rw.pacer.Call(func() (bool, error) {
+		obj := rw.bucket.Object(name)
+		key, _, err := rw.keyFor(tenantID)
+		if err != nil {
+			return false, err
+		}
+		if len(key) > 0 {
+			obj = obj.Key(key)
+		}
+
+		r, err := obj.NewReader(ctx)
+		if err != nil {
+			return shouldRetry(err), err
+		}
+		defer r.Close()
+
+		b, err = ioutil.ReadAll(r)
+		if err != nil {
+			return shouldRetry(err), err
+		}
+
+		return false, nil
+	})
 	if err != nil {
-		return nil, time.Time{}, err
+		err = fmt.Errorf("read %s: %w", name, err)
+		log.LogIf(ctx, err)
 	}
 
-	return bytes, r.Attrs.LastModified, nil
+	return b, err
 }
 
-func (rw *readerWriter) readRange(ctx context.Context, name string, offset int64, buffer []byte) error {
-	r, err := rw.bucket.Object(name).NewRangeReader(ctx, offset, int64(len(buffer)))
-	if err != nil {
-		return err
-	}
-	defer r.Close()
+func (rw *readerWriter) readAllWithModTime(ctx context.Context, name string, tenantID string) ([]byte, time.Time, error) {
+	var b []byte
+	var modTime time.Time
 
-	totalBytes := 0
-	for {
-		byteCount, err := r.Read(buffer[totalBytes:])
-		if err == io.EOF {
-			return nil
+	err := rw.pacer.Call(func() (bool, error) {
+		obj := rw.bucket.Object(name)
+		key, _, err := rw.keyFor(tenantID)
+		if err != nil {
+			return false, err
+		}
+		if len(key) > 0 {
+			obj = obj.Key(key)
 		}
+
+		r, err := obj.NewReader(ctx)
 		if err != nil {
-			return err
+			return shouldRetry(err), err
 		}
-		if byteCount == 0 {
-			return nil
+		defer r.Close()
+
+		b, err = ioutil.ReadAll(r)
+		if err != nil {
+			return shouldRetry(err), err
 		}
-		totalBytes += byteCount
-	}
+
+		modTime = r.Attrs.LastModified
+		return false, nil
+	})
+
+	return b, modTime, err
 }
 
 func fileExists(filename string) bool {
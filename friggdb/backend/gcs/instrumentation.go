@@ -0,0 +1,27 @@
+package gcs
+
+import (
+	"context"
+	"net/http"
+
+	"go.opencensus.io/plugin/ochttp"
+	"google.golang.org/api/option"
+	"google.golang.org/api/transport"
+)
+
+// instrumentation builds a client option that wraps the default GCS transport
+// with an ochttp transport so that requests to the bucket show up in tracing.
+func instrumentation(ctx context.Context, scope string) (option.ClientOption, error) {
+	base, err := transport.NewHTTPClient(ctx, option.WithScopes(scope))
+	if err != nil {
+		return nil, err
+	}
+
+	instrumented := &http.Client{
+		Transport: &ochttp.Transport{
+			Base: base.Transport,
+		},
+	}
+
+	return option.WithHTTPClient(instrumented), nil
+}
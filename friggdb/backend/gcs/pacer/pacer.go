@@ -0,0 +1,92 @@
+// Package pacer implements a sleep-and-retry pacer for smoothing over transient
+// errors from the GCS API, modeled on rclone's fs/fserrors pacer.
+package pacer
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Config controls the pacer's backoff behaviour.
+type Config struct {
+	MinSleep      time.Duration // initial sleep between retries
+	MaxSleep      time.Duration // maximum sleep between retries
+	MaxRetries    int           // maximum number of calls, the first one included, before giving up
+	DecayConstant uint          // sleep grows by a factor of 2^DecayConstant after each retryable error
+}
+
+// Pacer sleeps a variable amount of time between calls to smooth over
+// transient errors returned by a remote API.
+type Pacer struct {
+	mu    sync.Mutex
+	cfg   Config
+	sleep time.Duration
+}
+
+// New creates a Pacer with the given config, falling back to sane defaults for
+// any zero values.
+func New(cfg Config) *Pacer {
+	if cfg.MinSleep <= 0 {
+		cfg.MinSleep = 10 * time.Millisecond
+	}
+	if cfg.MaxSleep <= 0 {
+		cfg.MaxSleep = 2 * time.Second
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 10
+	}
+	if cfg.DecayConstant == 0 {
+		cfg.DecayConstant = 2
+	}
+
+	return &Pacer{
+		cfg:   cfg,
+		sleep: cfg.MinSleep,
+	}
+}
+
+// Call calls fn, retrying with an exponential backoff plus jitter as long as fn
+// reports the error as retryable and the retry budget isn't exhausted.
+func (p *Pacer) Call(fn func() (retry bool, err error)) error {
+	var err error
+
+	for retries := 0; retries < p.cfg.MaxRetries; retries++ {
+		var retry bool
+		retry, err = fn()
+		if !retry {
+			if err == nil {
+				p.reset()
+			}
+			return err
+		}
+
+		p.sleepAfterError()
+	}
+
+	return err
+}
+
+// sleepAfterError sleeps for a jittered duration between half and all of the
+// current backoff, then grows the backoff towards MaxSleep. Jitter keeps many
+// clients from retrying in lockstep.
+func (p *Pacer) sleepAfterError() {
+	p.mu.Lock()
+	sleep := p.sleep
+	p.sleep *= time.Duration(1 << p.cfg.DecayConstant)
+	if p.sleep > p.cfg.MaxSleep {
+		p.sleep = p.cfg.MaxSleep
+	}
+	p.mu.Unlock()
+
+	jitter := time.Duration(rand.Int63n(int64(sleep) + 1))
+	time.Sleep(sleep/2 + jitter/2)
+}
+
+// reset puts the pacer back to its initial sleep duration, used after a call
+// succeeds so the next transient error starts backing off from MinSleep again.
+func (p *Pacer) reset() {
+	p.mu.Lock()
+	p.sleep = p.cfg.MinSleep
+	p.mu.Unlock()
+}
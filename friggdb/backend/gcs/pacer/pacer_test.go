@@ -0,0 +1,119 @@
+package pacer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCallSucceedsWithoutRetry(t *testing.T) {
+	p := New(Config{})
+
+	calls := 0
+	err := p.Call(func() (bool, error) {
+		calls++
+		return false, nil
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestCallRetriesUntilSuccess(t *testing.T) {
+	p := New(Config{MinSleep: time.Microsecond, MaxSleep: time.Millisecond, MaxRetries: 5})
+
+	calls := 0
+	err := p.Call(func() (bool, error) {
+		calls++
+		if calls < 3 {
+			return true, errors.New("transient")
+		}
+		return false, nil
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestCallGivesUpAfterMaxRetries(t *testing.T) {
+	p := New(Config{MinSleep: time.Microsecond, MaxSleep: time.Millisecond, MaxRetries: 3})
+
+	calls := 0
+	wantErr := errors.New("always transient")
+	err := p.Call(func() (bool, error) {
+		calls++
+		return true, wantErr
+	})
+
+	if err != wantErr {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected MaxRetries (3) calls, got %d", calls)
+	}
+}
+
+func TestCallDoesNotRetryNonRetryableError(t *testing.T) {
+	p := New(Config{MinSleep: time.Microsecond, MaxSleep: time.Millisecond, MaxRetries: 5})
+
+	calls := 0
+	wantErr := errors.New("permanent")
+	err := p.Call(func() (bool, error) {
+		calls++
+		return false, wantErr
+	})
+
+	if err != wantErr {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestSleepGrowsAndCapsAtMaxSleep(t *testing.T) {
+	p := New(Config{MinSleep: time.Millisecond, MaxSleep: 4 * time.Millisecond, DecayConstant: 1})
+
+	p.sleepAfterError()
+	if p.sleep != 2*time.Millisecond {
+		t.Fatalf("expected sleep to double to 2ms, got %v", p.sleep)
+	}
+
+	p.sleepAfterError()
+	if p.sleep != 4*time.Millisecond {
+		t.Fatalf("expected sleep to double to 4ms, got %v", p.sleep)
+	}
+
+	p.sleepAfterError()
+	if p.sleep != 4*time.Millisecond {
+		t.Fatalf("expected sleep to stay capped at MaxSleep (4ms), got %v", p.sleep)
+	}
+}
+
+func TestResetOnSuccessReturnsSleepToMinSleep(t *testing.T) {
+	p := New(Config{MinSleep: time.Millisecond, MaxSleep: time.Second, DecayConstant: 1})
+
+	p.sleepAfterError()
+	if p.sleep == p.cfg.MinSleep {
+		t.Fatalf("expected sleep to have grown past MinSleep")
+	}
+
+	err := p.Call(func() (bool, error) {
+		return false, nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if p.sleep != p.cfg.MinSleep {
+		t.Fatalf("expected sleep to reset to MinSleep (%v), got %v", p.cfg.MinSleep, p.sleep)
+	}
+}
@@ -0,0 +1,33 @@
+package gcs
+
+import (
+	"net"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+)
+
+// shouldRetry returns true if err looks like a transient GCS error worth
+// retrying: 408/429/5xx API responses or a network-level timeout.
+func shouldRetry(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if gerr, ok := err.(*googleapi.Error); ok {
+		switch gerr.Code {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return true
+		}
+		if gerr.Code >= 500 && gerr.Code < 600 {
+			return true
+		}
+		return false
+	}
+
+	if nerr, ok := err.(net.Error); ok {
+		return nerr.Timeout()
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package gcs
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+type fakeTimeoutError struct{}
+
+func (fakeTimeoutError) Error() string   { return "timeout" }
+func (fakeTimeoutError) Timeout() bool   { return true }
+func (fakeTimeoutError) Temporary() bool { return true }
+
+var _ net.Error = fakeTimeoutError{}
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error", nil, false},
+		{"408 request timeout", &googleapi.Error{Code: http.StatusRequestTimeout}, true},
+		{"429 too many requests", &googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{"500 internal server error", &googleapi.Error{Code: http.StatusInternalServerError}, true},
+		{"503 service unavailable", &googleapi.Error{Code: http.StatusServiceUnavailable}, true},
+		{"404 not found", &googleapi.Error{Code: http.StatusNotFound}, false},
+		{"400 bad request", &googleapi.Error{Code: http.StatusBadRequest}, false},
+		{"net timeout", fakeTimeoutError{}, true},
+		{"other error", fmt.Errorf("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldRetry(tt.err); got != tt.want {
+				t.Errorf("shouldRetry(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package s3
+
+// Config holds the S3 backend configuration.
+type Config struct {
+	Bucket    string `yaml:"bucket"`
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	Insecure  bool   `yaml:"insecure"`
+	PartSize  int64  `yaml:"part_size"`
+}
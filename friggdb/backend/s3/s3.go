@@ -0,0 +1,258 @@
+package s3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/google/uuid"
+	"github.com/grafana/frigg/friggdb/backend"
+)
+
+type readerWriter struct {
+	cfg        *Config
+	session    *session.Session
+	s3         *s3.S3
+	uploader   *s3manager.Uploader
+	downloader *s3manager.Downloader
+}
+
+// New creates a backend.Reader, backend.Writer and backend.Compactor backed by S3.
+func New(cfg *Config) (backend.Reader, backend.Writer, backend.Compactor, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:           aws.String(cfg.Region),
+		Endpoint:         aws.String(cfg.Endpoint),
+		Credentials:      credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, ""),
+		DisableSSL:       aws.Bool(cfg.Insecure),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rw := &readerWriter{
+		cfg:        cfg,
+		session:    sess,
+		s3:         s3.New(sess),
+		uploader:   s3manager.NewUploader(sess),
+		downloader: s3manager.NewDownloader(sess),
+	}
+
+	return rw, rw, rw, nil
+}
+
+func (rw *readerWriter) Write(ctx context.Context, blockID uuid.UUID, tenantID string, meta *backend.BlockMeta, bBloom []byte, bIndex []byte, objectFilePath string) error {
+	err := rw.writeAll(ctx, rw.bloomFileName(blockID, tenantID), bBloom)
+	if err != nil {
+		return err
+	}
+
+	err = rw.writeAll(ctx, rw.indexFileName(blockID, tenantID), bIndex)
+	if err != nil {
+		return err
+	}
+
+	// copy traces file.
+	src, err := os.Open(objectFilePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = rw.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(rw.cfg.Bucket),
+		Key:    aws.String(rw.objectFileName(blockID, tenantID)),
+		Body:   src,
+	})
+	if err != nil {
+		return err
+	}
+
+	bMeta, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	// write meta last.  this will prevent blocklist from returning a partial block
+	return rw.writeAll(ctx, rw.metaFileName(blockID, tenantID), bMeta)
+}
+
+func (rw *readerWriter) Tenants() ([]string, error) {
+	var warning error
+	tenants := make([]string, 0)
+
+	err := rw.s3.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket:    aws.String(rw.cfg.Bucket),
+		Delimiter: aws.String("/"),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, p := range page.CommonPrefixes {
+			tenants = append(tenants, strings.TrimSuffix(aws.StringValue(p.Prefix), "/"))
+		}
+		return true
+	})
+	if err != nil {
+		warning = err
+	}
+
+	return tenants, warning
+}
+
+func (rw *readerWriter) Blocks(tenantID string) ([]uuid.UUID, error) {
+	var warning error
+	blocks := make([]uuid.UUID, 0)
+
+	err := rw.s3.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket:    aws.String(rw.cfg.Bucket),
+		Prefix:    aws.String(tenantID + "/"),
+		Delimiter: aws.String("/"),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, p := range page.CommonPrefixes {
+			idString := strings.TrimSuffix(strings.TrimPrefix(aws.StringValue(p.Prefix), tenantID+"/"), "/")
+			blockID, err := uuid.Parse(idString)
+			if err != nil {
+				warning = fmt.Errorf("failed parse on blockID %s: %v", idString, err)
+				continue
+			}
+			blocks = append(blocks, blockID)
+		}
+		return true
+	})
+	if err != nil {
+		warning = err
+	}
+
+	return blocks, warning
+}
+
+func (rw *readerWriter) BlockMeta(blockID uuid.UUID, tenantID string) (*backend.BlockMeta, error) {
+	name := rw.metaFileName(blockID, tenantID)
+
+	bytes, err := rw.readAll(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &backend.BlockMeta{}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (rw *readerWriter) Bloom(blockID uuid.UUID, tenantID string) ([]byte, error) {
+	name := rw.bloomFileName(blockID, tenantID)
+	return rw.readAll(context.Background(), name)
+}
+
+func (rw *readerWriter) Index(blockID uuid.UUID, tenantID string) ([]byte, error) {
+	name := rw.indexFileName(blockID, tenantID)
+	return rw.readAll(context.Background(), name)
+}
+
+func (rw *readerWriter) Object(blockID uuid.UUID, tenantID string, start uint64, buffer []byte) error {
+	name := rw.objectFileName(blockID, tenantID)
+	return rw.readRange(context.Background(), name, int64(start), buffer)
+}
+
+func (rw *readerWriter) ObjectStream(blockID uuid.UUID, tenantID string, start int64, length int64) (io.ReadCloser, error) {
+	name := rw.objectFileName(blockID, tenantID)
+	byteRange := fmt.Sprintf("bytes=%d-%d", start, start+length-1)
+
+	out, err := rw.s3.GetObjectWithContext(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(rw.cfg.Bucket),
+		Key:    aws.String(name),
+		Range:  aws.String(byteRange),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Body, nil
+}
+
+func (rw *readerWriter) Shutdown() {
+
+}
+
+func (rw *readerWriter) metaFileName(blockID uuid.UUID, tenantID string) string {
+	return path.Join(rw.rootPath(blockID, tenantID), "meta.json")
+}
+
+func (rw *readerWriter) bloomFileName(blockID uuid.UUID, tenantID string) string {
+	return path.Join(rw.rootPath(blockID, tenantID), "bloom")
+}
+
+func (rw *readerWriter) indexFileName(blockID uuid.UUID, tenantID string) string {
+	return path.Join(rw.rootPath(blockID, tenantID), "index")
+}
+
+func (rw *readerWriter) objectFileName(blockID uuid.UUID, tenantID string) string {
+	return path.Join(rw.rootPath(blockID, tenantID), "data")
+}
+
+func (rw *readerWriter) rootPath(blockID uuid.UUID, tenantID string) string {
+	return path.Join(tenantID, blockID.String())
+}
+
+func (rw *readerWriter) writeAll(ctx context.Context, name string, b []byte) error {
+	_, err := rw.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(rw.cfg.Bucket),
+		Key:    aws.String(name),
+		Body:   strings.NewReader(string(b)),
+	})
+	return err
+}
+
+func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, error) {
+	out, err := rw.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(rw.cfg.Bucket),
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	return ioutil.ReadAll(out.Body)
+}
+
+func (rw *readerWriter) readRange(ctx context.Context, name string, offset int64, buffer []byte) error {
+	byteRange := fmt.Sprintf("bytes=%d-%d", offset, offset+int64(len(buffer))-1)
+
+	out, err := rw.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(rw.cfg.Bucket),
+		Key:    aws.String(name),
+		Range:  aws.String(byteRange),
+	})
+	if err != nil {
+		return err
+	}
+	defer out.Body.Close()
+
+	totalBytes := 0
+	for {
+		byteCount, err := out.Body.Read(buffer[totalBytes:])
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if byteCount == 0 {
+			return nil
+		}
+		totalBytes += byteCount
+	}
+}
@@ -0,0 +1,43 @@
+// Package log provides structured, context-scoped logging for friggdb backends,
+// modeled on minio's logger package: callers attach request-scoped fields to a
+// context.Context once, and LogIf pulls them onto every error logged against it.
+package log
+
+import (
+	"context"
+	"os"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+)
+
+type ctxKeyType struct{}
+
+var ctxKey = ctxKeyType{}
+
+var logger = level.NewFilter(log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr)), level.AllowInfo())
+
+// WithFields returns a context carrying kv pairs (e.g. "tenantID", tenantID,
+// "blockID", blockID.String(), "operation", "Write") that LogIf will attach to
+// any error logged against the returned context.
+func WithFields(ctx context.Context, kv ...interface{}) context.Context {
+	return context.WithValue(ctx, ctxKey, append(fieldsFrom(ctx), kv...))
+}
+
+// LogIf logs err at warn level, along with any fields attached to ctx via
+// WithFields and any additional kv pairs, if err is non-nil.
+func LogIf(ctx context.Context, err error, kv ...interface{}) {
+	if err == nil {
+		return
+	}
+
+	args := append([]interface{}{"err", err}, fieldsFrom(ctx)...)
+	args = append(args, kv...)
+
+	level.Warn(logger).Log(args...)
+}
+
+func fieldsFrom(ctx context.Context) []interface{} {
+	fields, _ := ctx.Value(ctxKey).([]interface{})
+	return fields
+}
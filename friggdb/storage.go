@@ -0,0 +1,35 @@
+package friggdb
+
+import (
+	"fmt"
+
+	"github.com/grafana/frigg/friggdb/backend"
+	"github.com/grafana/frigg/friggdb/backend/azure"
+	"github.com/grafana/frigg/friggdb/backend/gcs"
+	"github.com/grafana/frigg/friggdb/backend/s3"
+)
+
+// Config selects and configures the storage backend used for blocks.
+type Config struct {
+	Backend string        `yaml:"backend"`
+	GCS     *gcs.Config   `yaml:"gcs"`
+	S3      *s3.Config    `yaml:"s3"`
+	Azure   *azure.Config `yaml:"azure"`
+}
+
+// NewBackend creates a backend.Reader, backend.Writer and backend.Compactor for the
+// backend selected in cfg.Backend.  Every higher-level component (blocklist, compactor,
+// ingester flush) only ever talks to these interfaces, so it works unchanged regardless
+// of which backend is selected.
+func NewBackend(cfg *Config) (backend.Reader, backend.Writer, backend.Compactor, error) {
+	switch cfg.Backend {
+	case "gcs":
+		return gcs.New(cfg.GCS)
+	case "s3":
+		return s3.New(cfg.S3)
+	case "azure":
+		return azure.New(cfg.Azure)
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown backend %s", cfg.Backend)
+	}
+}